@@ -5,30 +5,73 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/phzeng0726/yaml2go/pkg/generator"
 )
 
 func main() {
-	input := flag.String("i", "", "Path to YAML input file")
+	input := flag.String("i", "", "Path to a YAML file, a directory, or a glob pattern (e.g. \"manifests/*.yaml\")")
 	output := flag.String("o", "", "Path to output Go file (optional, default to stdout)")
 	structName := flag.String("struct", "YAMLToGoStruct", "Name of the Go struct")
 	withJsonTag := flag.Bool("json", false, "Whether to include JSON tags in the struct fields")
+	k8sMode := flag.Bool("k8s", false, "Name structs after each manifest's kind (Kubernetes apiVersion/kind convention)")
+	verify := flag.Bool("verify", false, "Round-trip the generated struct against its source YAML and report any lost fields, type mismatches, or precision loss")
 
 	flag.Parse()
 
+	opts := generator.Options{KubernetesMode: *k8sMode}
+
 	if *input == "" {
 		log.Fatal("input file is required")
 	}
 
-	data, err := os.ReadFile(*input)
+	files, err := resolveInputFiles(*input)
 	if err != nil {
-		log.Fatalf("failed to read file: %v", err)
+		log.Fatalf("failed to resolve input: %v", err)
+	}
+
+	used := map[string]int{}
+	sources := make([]generator.Source, 0, len(files))
+	names := make([]string, 0, len(files))
+	contents := make([]string, 0, len(files))
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatalf("failed to read file: %v", err)
+		}
+
+		name := *structName
+		if len(files) > 1 {
+			name += fileStructSuffix(file)
+			if n := used[name]; n > 0 {
+				name = fmt.Sprintf("%s%d", name, n+1)
+			}
+			used[name]++
+		}
+
+		sources = append(sources, generator.Source{Content: string(data), StructName: name})
+		names = append(names, name)
+		contents = append(contents, string(data))
 	}
 
-	code, err := generator.GenerateGoStruct(string(data), *structName, withJsonTag)
+	code, err := generator.GenerateMergedGoStructs(sources, withJsonTag, opts)
 	if err != nil {
-		log.Fatalf("failed to generate go struct: %v", err)
+		log.Fatalf("failed to generate go structs: %v", err)
+	}
+
+	if *verify {
+		for i, file := range files {
+			generated, err := generator.GenerateGoStructWithOptions(contents[i], names[i], withJsonTag, opts)
+			if err != nil {
+				log.Fatalf("failed to generate go struct for %s: %v", file, err)
+			}
+			printVerifyReport(file, contents[i], generated, names[i])
+		}
 	}
 
 	if *output != "" {
@@ -41,3 +84,81 @@ func main() {
 		fmt.Print(code)
 	}
 }
+
+// resolveInputFiles expands -i into a concrete, sorted list of YAML files.
+// It accepts a single file, a glob pattern, or a directory (in which case
+// every *.yaml/*.yml file directly inside it is used).
+func resolveInputFiles(input string) ([]string, error) {
+	if info, err := os.Stat(input); err == nil {
+		if !info.IsDir() {
+			return []string{input}, nil
+		}
+		var files []string
+		for _, ext := range []string{"*.yaml", "*.yml"} {
+			matches, err := filepath.Glob(filepath.Join(input, ext))
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, matches...)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no .yaml/.yml files found in directory %s", input)
+		}
+		sort.Strings(files)
+		return files, nil
+	}
+
+	matches, err := filepath.Glob(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no files match %s", input)
+	}
+	return matches, nil
+}
+
+// printVerifyReport round-trips file's generated struct against its source
+// YAML and reports the result on stderr. A multi-document file generates
+// DocN-suffixed struct names that won't match name, so a "not found" error
+// there just means verification is skipped for that file.
+func printVerifyReport(file, yamlContent, generatedCode, structName string) {
+	report, err := generator.Verify(yamlContent, generatedCode, structName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verify %s: %v\n", file, err)
+		return
+	}
+
+	if report.OK() {
+		fmt.Fprintf(os.Stderr, "verify %s: %s round-trips losslessly\n", file, structName)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "verify %s: %s did not round-trip losslessly\n", file, structName)
+	for _, f := range report.LostFields {
+		fmt.Fprintf(os.Stderr, "  lost field: %s\n", f)
+	}
+	for _, m := range report.TypeMismatches {
+		fmt.Fprintf(os.Stderr, "  type mismatch: %s\n", m)
+	}
+	for _, p := range report.PrecisionLoss {
+		fmt.Fprintf(os.Stderr, "  precision loss: %s\n", p)
+	}
+}
+
+var nonAlnumRE = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// fileStructSuffix derives a CamelCase struct-name suffix from a YAML
+// file's base name, e.g. "deployment-01.yaml" -> "Deployment01".
+func fileStructSuffix(file string) string {
+	base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	parts := nonAlnumRE.Split(base, -1)
+	var b strings.Builder
+	for _, p := range parts {
+		if p == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(p[:1]) + p[1:])
+	}
+	return b.String()
+}
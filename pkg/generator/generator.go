@@ -3,9 +3,11 @@ package generator
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -16,7 +18,85 @@ type yamlEntry struct {
 	Value   string
 	Comment string
 	Kind    yaml.Kind
-	Node    *yaml.Node // Reference to the original node
+	Node    *yaml.Node // Reference to the original node (an alias node is kept as is; Kind is that of its target)
+}
+
+// tracks struct names already assigned to mapping nodes so that shared
+// anchors/aliases reuse a single Go type instead of regenerating it, and
+// so that cyclic references can be broken with a pointer field. It also
+// dedupes structurally identical structs (same field set and types) that
+// arise from unrelated parts of the tree, keyed by a signature of their
+// rows rather than by node identity.
+type genContext struct {
+	structNames map[*yaml.Node]string
+	visiting    map[*yaml.Node]bool
+	signatures  map[string]string
+	imports     map[string]bool
+}
+
+func newGenContext() *genContext {
+	return &genContext{
+		structNames: make(map[*yaml.Node]string),
+		visiting:    make(map[*yaml.Node]bool),
+		signatures:  make(map[string]string),
+		imports:     make(map[string]bool),
+	}
+}
+
+// a single generated struct field, in the form finalize/renderStruct need
+// to emit it and hash it for structural dedup.
+type structRow struct {
+	FieldName string
+	FieldType string
+	YamlKey   string
+	JsonTag   bool
+	OmitEmpty bool
+	Comment   string
+}
+
+func renderStruct(indent, name string, rows []structRow) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%stype %s struct {\n", indent, name)
+	for _, r := range rows {
+		omit := ""
+		if r.OmitEmpty {
+			omit = ",omitempty"
+		}
+		jsonTag := ""
+		if r.JsonTag {
+			jsonTag = fmt.Sprintf(" json:\"%s%s\"", r.YamlKey, omit)
+		}
+		comment := ""
+		if r.Comment != "" {
+			comment = fmt.Sprintf(" // %s", r.Comment)
+		}
+		fmt.Fprintf(&b, "%s\t%s %s `yaml:\"%s%s\"%s`%s\n", indent, r.FieldName, r.FieldType, r.YamlKey, omit, jsonTag, comment)
+	}
+	fmt.Fprintf(&b, "%s}\n\n", indent)
+	return b.String()
+}
+
+// structSignature normalizes a struct's rows (fields are already sorted by
+// key) into a string that is equal for two structs iff they declare the
+// same fields with the same types and optionality.
+func structSignature(rows []structRow) string {
+	parts := make([]string, len(rows))
+	for i, r := range rows {
+		parts[i] = fmt.Sprintf("%s:%s:%v", r.YamlKey, r.FieldType, r.OmitEmpty)
+	}
+	return strings.Join(parts, "|")
+}
+
+// finalize either renders a brand-new named struct for rows, or - if an
+// existing struct with an identical signature was already emitted - skips
+// emitting it and reuses that struct's name instead.
+func finalize(ctx *genContext, indent string, wantName string, rows []structRow, subStructs string) (code string, usedName string) {
+	sig := structSignature(rows)
+	if existing, ok := ctx.signatures[sig]; ok {
+		return subStructs, existing
+	}
+	ctx.signatures[sig] = wantName
+	return renderStruct(indent, wantName, rows) + subStructs, wantName
 }
 
 // converts snake_case to CamelCase
@@ -45,23 +125,136 @@ func toCamel(s string) string {
 	return strings.Join(parts, "")
 }
 
+// resolveAlias follows a yaml.AliasNode to the node it points to. Non-alias
+// nodes are returned unchanged.
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		return node.Alias
+	}
+	return node
+}
+
+// TypeInferrer decides the Go type for a scalar YAML node. It returns
+// ok=false to defer to the next inferrer in the chain, letting the default
+// behavior (or another registered inferrer) take over. requiredImport is
+// the import path the returned type needs (e.g. "time"), or "" if none.
+type TypeInferrer interface {
+	Infer(node *yaml.Node) (goType string, requiredImport string, ok bool)
+}
+
+// InferrerFunc adapts a plain function to a TypeInferrer.
+type InferrerFunc func(node *yaml.Node) (goType string, requiredImport string, ok bool)
+
+func (f InferrerFunc) Infer(node *yaml.Node) (string, string, bool) {
+	return f(node)
+}
+
+// builtinInferrers run, in order, before any inferrer registered via
+// RegisterInferrer, and before the default int/float64/bool/string fallback.
+var builtinInferrers = []TypeInferrer{
+	binaryInferrer,
+	timeInferrer,
+	durationInferrer,
+}
+
+var customInferrers []TypeInferrer
+
+// RegisterInferrer adds a project-specific scalar-type rule, consulted
+// after the built-in detectors (time.Time, time.Duration, and string for
+// !!binary scalars) and
+// before the plain int/float64/bool/string fallback. For example, a caller
+// could detect dotted-quad strings and infer net.IP.
+func RegisterInferrer(inferrer TypeInferrer) {
+	customInferrers = append(customInferrers, inferrer)
+}
+
+// binaryInferrer recognizes !!binary-tagged scalars. yaml.v3 only special-
+// cases !!binary when unmarshaling into a string field (the raw base64 text
+// round-trips there); it errors on a []byte field ("cannot unmarshal
+// !!binary into []uint8"), so string is the type that's actually usable.
+var binaryInferrer = InferrerFunc(func(node *yaml.Node) (string, string, bool) {
+	if node.Tag == "!!binary" {
+		return "string", "", true
+	}
+	return "", "", false
+})
+
+var timeInferrer = InferrerFunc(func(node *yaml.Node) (string, string, bool) {
+	// A bare (unquoted) RFC3339 scalar - the idiomatic way to write a YAML
+	// timestamp - is tagged !!timestamp by yaml.v3, not !!str; only a quoted
+	// one is tagged !!str. Accept both.
+	if (node.Tag != "!!str" && node.Tag != "!!timestamp" && node.Tag != "") || node.Value == "" {
+		return "", "", false
+	}
+	if _, err := time.Parse(time.RFC3339, node.Value); err != nil {
+		return "", "", false
+	}
+	return "time.Time", "time", true
+})
+
+// durationRE limits candidate scalars to number-plus-unit literals such as
+// "5m" or "2h30m" before the time.ParseDuration call, which on its own
+// also accepts forms like a bare "0" or a leading "+".
+var durationRE = regexp.MustCompile(`^-?([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`)
+
+var durationInferrer = InferrerFunc(func(node *yaml.Node) (string, string, bool) {
+	if (node.Tag != "!!str" && node.Tag != "") || !durationRE.MatchString(node.Value) {
+		return "", "", false
+	}
+	if _, err := time.ParseDuration(node.Value); err != nil {
+		return "", "", false
+	}
+	return "time.Duration", "time", true
+})
+
+// scalarGoType resolves a scalar node's Go type by walking the built-in,
+// then custom, then default inferrers, recording any import the winning
+// type needs.
+func scalarGoType(node *yaml.Node, ctx *genContext) string {
+	for _, inf := range builtinInferrers {
+		if t, imp, ok := inf.Infer(node); ok {
+			if imp != "" {
+				ctx.imports[imp] = true
+			}
+			return t
+		}
+	}
+	for _, inf := range customInferrers {
+		if t, imp, ok := inf.Infer(node); ok {
+			if imp != "" {
+				ctx.imports[imp] = true
+			}
+			return t
+		}
+	}
+
+	switch node.Tag {
+	case "!!int":
+		return "int"
+	case "!!float":
+		return "float64"
+	case "!!bool":
+		return "bool"
+	case "!!null":
+		// A bare null carries no type information on its own; unification
+		// (which sees the field's other occurrences) may still turn this
+		// into a concrete pointer type.
+		return "interface{}"
+	default:
+		return "string"
+	}
+}
+
 // determines the corresponding Go type for a YAML node
-func determineGoType(node *yaml.Node) string {
+func determineGoType(node *yaml.Node, ctx *genContext) string {
+	node = resolveAlias(node)
+
 	switch node.Kind {
 	case yaml.ScalarNode:
-		switch node.Tag {
-		case "!!int":
-			return "int"
-		case "!!float":
-			return "float64"
-		case "!!bool":
-			return "bool"
-		default:
-			return "string"
-		}
+		return scalarGoType(node, ctx)
 	case yaml.SequenceNode:
 		if len(node.Content) > 0 {
-			elemType := determineGoType(node.Content[0])
+			elemType := determineGoType(node.Content[0], ctx)
 			return "[]" + elemType
 		}
 		return "[]interface{}"
@@ -72,25 +265,134 @@ func determineGoType(node *yaml.Node) string {
 	}
 }
 
+// isNullScalar reports whether node is an explicit `null`/`~` or a scalar
+// whose value is empty or blank, whatever its tag (a quoted "" counts too) -
+// the shapes unifyMappingSet treats as "no value" when
+// deciding whether a field needs a pointer type.
+func isNullScalar(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && (node.Tag == "!!null" || strings.TrimSpace(node.Value) == "")
+}
+
+// expandMergeKeys returns the mapping node's content (key/value pairs,
+// flattened the same way yaml.Node stores them) with any `<<:` merge keys
+// inlined. Explicit local keys always win over merged-in keys; when several
+// anchors are merged, earlier ones take priority over later ones, matching
+// the YAML merge-key convention.
+func expandMergeKeys(node *yaml.Node) ([]*yaml.Node, error) {
+	return expandMergeKeysVisited(node, map[*yaml.Node]bool{})
+}
+
+// expandMergeKeysVisited does the work for expandMergeKeys, tracking nodes
+// currently being expanded so a merge key that (directly or transitively)
+// references its own mapping is reported as an error instead of recursing
+// forever.
+func expandMergeKeysVisited(node *yaml.Node, visited map[*yaml.Node]bool) ([]*yaml.Node, error) {
+	if visited[node] {
+		return nil, fmt.Errorf("cyclic merge key (`<<`) detected")
+	}
+	visited[node] = true
+	defer delete(visited, node)
+
+	type pair struct{ key, val *yaml.Node }
+
+	local := []pair{}
+	merged := []pair{}
+	seenLocal := map[string]bool{}
+
+	var mergeFrom func(mapNode *yaml.Node) error
+	mergeFrom = func(mapNode *yaml.Node) error {
+		mapNode = resolveAlias(mapNode)
+		if mapNode.Kind != yaml.MappingNode {
+			return fmt.Errorf("merge key target is not a mapping node")
+		}
+		sub, err := expandMergeKeysVisited(mapNode, visited)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < len(sub); i += 2 {
+			k, v := sub[i], sub[i+1]
+			if seenLocal[k.Value] {
+				continue
+			}
+			dup := false
+			for _, m := range merged {
+				if m.key.Value == k.Value {
+					dup = true
+					break
+				}
+			}
+			if !dup {
+				merged = append(merged, pair{k, v})
+			}
+		}
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		valNode := node.Content[i+1]
+
+		if keyNode.Value == "<<" {
+			switch resolveAlias(valNode).Kind {
+			case yaml.MappingNode:
+				if err := mergeFrom(valNode); err != nil {
+					return nil, err
+				}
+			case yaml.SequenceNode:
+				seq := resolveAlias(valNode)
+				for _, item := range seq.Content {
+					if err := mergeFrom(item); err != nil {
+						return nil, err
+					}
+				}
+			default:
+				return nil, fmt.Errorf("merge key `<<` must reference a mapping or sequence of mappings")
+			}
+			continue
+		}
+
+		local = append(local, pair{keyNode, valNode})
+		seenLocal[keyNode.Value] = true
+	}
+
+	content := make([]*yaml.Node, 0, (len(local)+len(merged))*2)
+	for _, p := range local {
+		content = append(content, p.key, p.val)
+	}
+	for _, p := range merged {
+		if seenLocal[p.key.Value] {
+			continue
+		}
+		content = append(content, p.key, p.val)
+	}
+
+	return content, nil
+}
+
 // extract sorted YAML entries
 func extractSortedYamlEntries(node *yaml.Node, structName string) ([]yamlEntry, error) {
 	if node.Kind != yaml.MappingNode {
 		return nil, fmt.Errorf("expected mapping node for struct %s", structName)
 	}
 
+	content, err := expandMergeKeys(node)
+	if err != nil {
+		return nil, err
+	}
+
 	entries := []yamlEntry{}
-	for i := 0; i < len(node.Content); i += 2 {
-		if i+1 >= len(node.Content) {
+	for i := 0; i < len(content); i += 2 {
+		if i+1 >= len(content) {
 			break
 		}
-		keyNode := node.Content[i]
-		valNode := node.Content[i+1]
+		keyNode := content[i]
+		valNode := content[i+1]
 
 		entries = append(entries, yamlEntry{
 			Key:     keyNode.Value,
 			Value:   valNode.Value,
 			Comment: strings.TrimSpace(valNode.LineComment),
-			Kind:    valNode.Kind,
+			Kind:    resolveAlias(valNode).Kind,
 			Node:    valNode,
 		})
 	}
@@ -103,74 +405,520 @@ func extractSortedYamlEntries(node *yaml.Node, structName string) ([]yamlEntry,
 	return entries, nil
 }
 
-// processes a YAML node and generates Go struct code
-func processNode(node *yaml.Node, structName string, indent string, withJsonTag *bool) (string, error) {
+// mappingElements returns the elements of a sequence node that are (after
+// resolving aliases) mapping nodes, preserving order.
+func mappingElements(seq *yaml.Node) []*yaml.Node {
+	var out []*yaml.Node
+	for _, item := range seq.Content {
+		if resolved := resolveAlias(item); resolved.Kind == yaml.MappingNode {
+			out = append(out, resolved)
+		}
+	}
+	return out
+}
+
+// processes a YAML node and generates Go struct code. It returns the name
+// the struct was actually emitted under, which may differ from structName
+// when an existing structurally-identical struct was reused instead.
+func processNode(node *yaml.Node, structName string, indent string, withJsonTag *bool, ctx *genContext) (string, string, error) {
 	entries, err := extractSortedYamlEntries(node, structName)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	// Generate Go struct
-	var b bytes.Buffer
-	fmt.Fprintf(&b, "%stype %s struct {\n", indent, structName)
-
+	rows := make([]structRow, 0, len(entries))
 	subStructs := ""
 
 	for _, e := range entries {
 		fieldName := toCamel(e.Key)
-		fieldType := determineGoType(e.Node)
-		comment := ""
-		if e.Comment != "" {
-			comment = fmt.Sprintf(" // %s", e.Comment)
-		}
+		fieldType := determineGoType(e.Node, ctx)
+		target := resolveAlias(e.Node)
 
-		if e.Kind == yaml.MappingNode {
+		switch {
+		case e.Kind == yaml.MappingNode:
 			subStructName := structName + fieldName
-			subStruct, err := processNode(e.Node, subStructName, indent, withJsonTag)
-			if err != nil {
-				return "", err
+
+			if ctx.visiting[target] {
+				// Cycle: the anchor we're pointing to is an ancestor of this
+				// node that is still being processed. Break the cycle with a
+				// pointer field instead of recursing forever.
+				if name, ok := ctx.structNames[target]; ok {
+					subStructName = name
+				}
+				fieldType = "*" + subStructName
+			} else if name, ok := ctx.structNames[target]; ok {
+				// Already generated elsewhere (shared anchor) - reuse it.
+				fieldType = name
+			} else {
+				ctx.structNames[target] = subStructName
+				ctx.visiting[target] = true
+				code, usedName, err := processNode(target, subStructName, indent, withJsonTag, ctx)
+				delete(ctx.visiting, target)
+				if err != nil {
+					return "", "", err
+				}
+				ctx.structNames[target] = usedName
+				subStructs += code
+				fieldType = usedName
 			}
-			subStructs += subStruct
-			fieldType = subStructName
-		} else if e.Kind == yaml.SequenceNode && len(e.Node.Content) > 0 && e.Node.Content[0].Kind == yaml.MappingNode {
-			// Handle array of objects
+		case e.Kind == yaml.SequenceNode && len(mappingElements(target)) > 0:
+			// Array of objects: unify the shape of every element rather than
+			// just looking at the first one.
 			subStructName := structName + fieldName
-			elemStruct, err := processNode(e.Node.Content[0], subStructName, indent, withJsonTag)
+			code, usedName, err := unifyMappingSetGuarded(mappingElements(target), subStructName, indent, withJsonTag, ctx)
 			if err != nil {
-				return "", err
+				return "", "", err
 			}
-			subStructs += elemStruct
-			fieldType = "[]" + subStructName
+			subStructs += code
+			fieldType = "[]" + usedName
 		}
 
-		// Add json tag if withJsonTag is true
-		jsonTag := ""
-		if *withJsonTag {
-			jsonTag = fmt.Sprintf(" json:\"%s\"", e.Key)
+		rows = append(rows, structRow{
+			FieldName: fieldName,
+			FieldType: fieldType,
+			YamlKey:   e.Key,
+			JsonTag:   *withJsonTag,
+			Comment:   e.Comment,
+		})
+	}
+
+	code, usedName := finalize(ctx, indent, structName, rows, subStructs)
+	return code, usedName, nil
+}
+
+// unifyMappingSetGuarded wraps unifyMappingSet with the same cycle
+// protection processNode applies to a single mapping node: if any of
+// elements is an ancestor still being processed (reached again through a
+// sequence field, e.g. `items: - self: *root`), the cycle is broken with a
+// pointer field instead of recursing forever.
+func unifyMappingSetGuarded(elements []*yaml.Node, structName string, indent string, withJsonTag *bool, ctx *genContext) (string, string, error) {
+	for _, el := range elements {
+		if ctx.visiting[el] {
+			name := structName
+			if existing, ok := ctx.structNames[el]; ok {
+				name = existing
+			}
+			return "", "*" + name, nil
 		}
-		fmt.Fprintf(&b, "%s\t%s %s `yaml:\"%s\"%s`%s\n", indent, fieldName, fieldType, e.Key, jsonTag, comment)
 	}
 
-	fmt.Fprintf(&b, "%s}\n\n", indent)
-	return b.String() + subStructs, nil
+	for _, el := range elements {
+		ctx.visiting[el] = true
+		if _, ok := ctx.structNames[el]; !ok {
+			ctx.structNames[el] = structName
+		}
+	}
+	code, usedName, err := unifyMappingSet(elements, structName, indent, withJsonTag, ctx)
+	for _, el := range elements {
+		delete(ctx.visiting, el)
+	}
+	return code, usedName, err
 }
 
-// generates Go struct code from YAML content
+// unifyMappingSet generates a single struct that covers every mapping in
+// elements: the field set is the union of all their keys, a field missing
+// from some elements is marked omitempty, and a field whose scalar type
+// disagrees across elements falls back to interface{}. A field that is
+// missing from some elements or null in some of them is marked omitempty
+// and, unless it is a slice, given a pointer type so nil can represent
+// "absent". Like processNode,
+// it returns the name the struct was actually emitted under.
+func unifyMappingSet(elements []*yaml.Node, structName string, indent string, withJsonTag *bool, ctx *genContext) (string, string, error) {
+	if len(elements) == 0 {
+		return "", "", fmt.Errorf("no mapping elements to unify for struct %s", structName)
+	}
+
+	// A pre-seeded name (e.g. from Kubernetes-mode GVK naming) on any one of
+	// these elements takes priority over the derived structName.
+	wantName := structName
+	for _, el := range elements {
+		if name, ok := ctx.structNames[el]; ok {
+			wantName = name
+			break
+		}
+	}
+
+	type fieldAgg struct {
+		key         string
+		occurrences []*yaml.Node
+		comment     string
+	}
+
+	order := []string{}
+	aggs := map[string]*fieldAgg{}
+
+	for _, el := range elements {
+		content, err := expandMergeKeys(el)
+		if err != nil {
+			return "", "", err
+		}
+		for i := 0; i+1 < len(content); i += 2 {
+			key := content[i].Value
+			val := content[i+1]
+
+			agg, ok := aggs[key]
+			if !ok {
+				agg = &fieldAgg{key: key}
+				aggs[key] = agg
+				order = append(order, key)
+			}
+			agg.occurrences = append(agg.occurrences, resolveAlias(val))
+			if agg.comment == "" {
+				agg.comment = strings.TrimSpace(val.LineComment)
+			}
+		}
+	}
+
+	sort.Strings(order)
+
+	total := len(elements)
+	rows := make([]structRow, 0, len(order))
+	subStructs := ""
+
+	for _, key := range order {
+		agg := aggs[key]
+		fieldName := toCamel(key)
+		optional := len(agg.occurrences) < total
+
+		allMapping, allSequence := true, true
+		for _, occ := range agg.occurrences {
+			allMapping = allMapping && occ.Kind == yaml.MappingNode
+			allSequence = allSequence && occ.Kind == yaml.SequenceNode
+		}
+
+		var fieldType string
+		switch {
+		case allMapping:
+			code, usedName, err := unifyMappingSetGuarded(agg.occurrences, wantName+fieldName, indent, withJsonTag, ctx)
+			if err != nil {
+				return "", "", err
+			}
+			subStructs += code
+			fieldType = usedName
+		case allSequence:
+			var innerElems []*yaml.Node
+			elemTypes := map[string]bool{}
+			for _, occ := range agg.occurrences {
+				innerElems = append(innerElems, mappingElements(occ)...)
+				if len(occ.Content) > 0 && resolveAlias(occ.Content[0]).Kind != yaml.MappingNode {
+					elemTypes[determineGoType(occ.Content[0], ctx)] = true
+				}
+			}
+			if len(innerElems) > 0 {
+				code, usedName, err := unifyMappingSetGuarded(innerElems, wantName+fieldName, indent, withJsonTag, ctx)
+				if err != nil {
+					return "", "", err
+				}
+				subStructs += code
+				fieldType = "[]" + usedName
+			} else {
+				// Every occurrence's element type must agree; a mix (e.g.
+				// one occurrence's sequence holding ints, another's holding
+				// strings) falls back to interface{} rather than guessing
+				// from whichever occurrence happened to come first.
+				elemType := "interface{}"
+				if len(elemTypes) == 1 {
+					for t := range elemTypes {
+						elemType = t
+					}
+				}
+				fieldType = "[]" + elemType
+			}
+		default:
+			// Only scalar occurrences feed the type union below. A mapping
+			// or sequence occurrence here means this field qualified for
+			// neither allMapping nor allSequence (some occurrences differ
+			// in kind), so determineGoType's "struct" placeholder - valid
+			// only where a caller replaces it - would otherwise leak into
+			// the rendered field type as invalid Go.
+			types := map[string]bool{}
+			hasNull := false
+			hasOtherKind := false
+			for _, occ := range agg.occurrences {
+				if isNullScalar(occ) {
+					hasNull = true
+					continue
+				}
+				if occ.Kind != yaml.ScalarNode {
+					hasOtherKind = true
+					continue
+				}
+				types[determineGoType(occ, ctx)] = true
+			}
+			if hasOtherKind || len(types) != 1 {
+				fieldType = "interface{}"
+			} else {
+				for t := range types {
+					fieldType = t
+				}
+			}
+			if hasNull {
+				// Null in at least one occurrence, a concrete type in the
+				// rest: the field needs a pointer so nil can stand in for
+				// the null/missing case.
+				optional = true
+			}
+		}
+
+		if optional && !strings.HasPrefix(fieldType, "[]") {
+			fieldType = "*" + fieldType
+		}
+
+		rows = append(rows, structRow{
+			FieldName: fieldName,
+			FieldType: fieldType,
+			YamlKey:   key,
+			JsonTag:   *withJsonTag,
+			OmitEmpty: optional,
+			Comment:   agg.comment,
+		})
+	}
+
+	code, usedName := finalize(ctx, indent, wantName, rows, subStructs)
+	for _, el := range elements {
+		ctx.structNames[el] = usedName
+	}
+	return code, usedName, nil
+}
+
+// rootMappingOf extracts the mapping node a decoded document is rooted at.
+func rootMappingOf(doc *yaml.Node) (*yaml.Node, error) {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0], nil
+	}
+	if doc.Kind == yaml.MappingNode {
+		return doc, nil
+	}
+	return nil, fmt.Errorf("invalid YAML format: expected mapping node")
+}
+
+// docHeaderName looks for a `# name: <value>` head comment on the document
+// and, if present, returns the value to use as the struct name.
+var docNameCommentRE = regexp.MustCompile(`(?m)^\s*#\s*name:\s*(\S+)\s*$`)
+
+func docHeaderName(doc *yaml.Node, root *yaml.Node) string {
+	for _, comment := range []string{doc.HeadComment, root.HeadComment} {
+		if m := docNameCommentRE.FindStringSubmatch(comment); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// Options controls optional generation modes beyond the library's default
+// behavior.
+type Options struct {
+	// KubernetesMode names each top-level struct after its manifest's `kind`
+	// (CamelCased) instead of structName whenever the document's root
+	// mapping carries both `apiVersion` and `kind` scalar keys. A `kind:
+	// List` document with an `items` sequence generates the element struct
+	// once (named after the first item's own `kind`, or <Kind>Item as a
+	// fallback) and references it as a `[]T` Items field.
+	KubernetesMode bool
+}
+
+// kubernetesGVK reports the `kind` of a mapping node when it looks like a
+// Kubernetes manifest, i.e. it carries both an `apiVersion` and a `kind`
+// scalar key.
+func kubernetesGVK(node *yaml.Node) (kind string, ok bool) {
+	if node.Kind != yaml.MappingNode {
+		return "", false
+	}
+
+	hasAPIVersion := false
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		val := resolveAlias(node.Content[i+1])
+		switch key {
+		case "apiVersion":
+			if val.Kind == yaml.ScalarNode {
+				hasAPIVersion = true
+			}
+		case "kind":
+			if val.Kind == yaml.ScalarNode {
+				kind = val.Value
+			}
+		}
+	}
+
+	return kind, hasAPIVersion && kind != ""
+}
+
+// firstListItem returns the first element of a `kind: List` manifest's
+// `items` sequence, or nil if there is no non-empty items sequence of
+// mappings.
+func firstListItem(node *yaml.Node) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "items" {
+			continue
+		}
+		items := resolveAlias(node.Content[i+1])
+		if items.Kind != yaml.SequenceNode || len(items.Content) == 0 {
+			return nil
+		}
+		elem := resolveAlias(items.Content[0])
+		if elem.Kind != yaml.MappingNode {
+			return nil
+		}
+		return elem
+	}
+	return nil
+}
+
+// generates Go struct code from YAML content, using the library's default
+// options.
 func GenerateGoStruct(yamlContent string, structName string, withJsonTag *bool) (string, error) {
-	var node yaml.Node
-	if err := yaml.Unmarshal([]byte(yamlContent), &node); err != nil {
+	return GenerateGoStructWithOptions(yamlContent, structName, withJsonTag, Options{})
+}
+
+// generates Go struct code from YAML content. A multi-document input (YAML
+// separated by `---`) produces one top-level struct per document: the name
+// is taken from a `# name: <value>` header comment when present, otherwise
+// structName is suffixed with Doc1, Doc2, ... in document order. See Options
+// for optional generation modes.
+func GenerateGoStructWithOptions(yamlContent string, structName string, withJsonTag *bool, opts Options) (string, error) {
+	ctx := newGenContext()
+	out, err := generateBody(ctx, yamlContent, structName, withJsonTag, opts)
+	if err != nil {
 		return "", err
 	}
+	return renderImportsHeader(ctx.imports) + out, nil
+}
+
+// Source is one named YAML input to GenerateMergedGoStructs.
+type Source struct {
+	Content    string
+	StructName string
+}
+
+// GenerateMergedGoStructs generates Go struct code for each source and
+// concatenates them into a single file, the way callers merging several
+// input files (e.g. the CLI's directory/glob mode) need: each source gets
+// its own fresh genContext, so struct names, signatures, and anchors don't
+// leak between unrelated files, but the required imports across all of them
+// are hoisted into a single deduped `import (...)` block instead of one per
+// source - repeating GenerateGoStructWithOptions's own header per source
+// would emit several `import (...)` blocks in one file, which is invalid Go.
+func GenerateMergedGoStructs(sources []Source, withJsonTag *bool, opts Options) (string, error) {
+	imports := map[string]bool{}
+	var out strings.Builder
+
+	for _, src := range sources {
+		ctx := newGenContext()
+		body, err := generateBody(ctx, src.Content, src.StructName, withJsonTag, opts)
+		if err != nil {
+			return "", err
+		}
+		for imp := range ctx.imports {
+			imports[imp] = true
+		}
+		out.WriteString(body)
+	}
+
+	return renderImportsHeader(imports) + out.String(), nil
+}
+
+// generateBody does the actual YAML-to-Go-source work shared by
+// GenerateGoStructWithOptions and GenerateMergedGoStructs, leaving import
+// rendering to the caller so it can be hoisted across multiple sources.
+func generateBody(ctx *genContext, yamlContent string, structName string, withJsonTag *bool, opts Options) (string, error) {
+	dec := yaml.NewDecoder(strings.NewReader(yamlContent))
+
+	var docs []*yaml.Node
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+		docs = append(docs, &doc)
+	}
+
+	if len(docs) == 0 {
+		return "", fmt.Errorf("invalid YAML format: empty document")
+	}
+
+	usedNames := map[string]int{}
+	var out strings.Builder
+
+	for i, doc := range docs {
+		rootNode, err := rootMappingOf(doc)
+		if err != nil {
+			return "", err
+		}
+
+		name := structName
+		named := false
+
+		if opts.KubernetesMode {
+			if kind, ok := kubernetesGVK(rootNode); ok {
+				name = toCamel(kind)
+				named = true
+
+				if kind == "List" {
+					if elem := firstListItem(rootNode); elem != nil {
+						elemName := name + "Item"
+						if elemKind, ok := kubernetesGVK(elem); ok {
+							elemName = toCamel(elemKind)
+						}
+						ctx.structNames[elem] = elemName
+					}
+				}
+			}
+		}
+
+		if !named && len(docs) > 1 {
+			if header := docHeaderName(doc, rootNode); header != "" {
+				name = toCamel(header)
+			} else {
+				name = fmt.Sprintf("%sDoc%d", structName, i+1)
+			}
+		}
+
+		if n := usedNames[name]; n > 0 {
+			name = fmt.Sprintf("%s%d", name, n+1)
+		}
+		usedNames[name]++
+
+		ctx.structNames[rootNode] = name
+		ctx.visiting[rootNode] = true
+		code, usedName, err := processNode(rootNode, name, "", withJsonTag, ctx)
+		delete(ctx.visiting, rootNode)
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(code)
+		if usedName != name {
+			// The document's shape matched an already emitted struct; still
+			// declare a top-level type under this document's own name.
+			fmt.Fprintf(&out, "type %s %s\n\n", name, usedName)
+		}
+	}
+
+	return out.String(), nil
+}
+
+// renderImportsHeader renders a single deduped, sorted `import (...)` block
+// for the given set of import paths, or "" if imports is empty.
+func renderImportsHeader(imports map[string]bool) string {
+	if len(imports) == 0 {
+		return ""
+	}
+
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
 
-	// Find the first mapping node
-	var rootNode *yaml.Node
-	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
-		rootNode = node.Content[0]
-	} else if node.Kind == yaml.MappingNode {
-		rootNode = &node
-	} else {
-		return "", fmt.Errorf("invalid YAML format: expected mapping node")
+	var header strings.Builder
+	header.WriteString("import (\n")
+	for _, path := range paths {
+		fmt.Fprintf(&header, "\t%q\n", path)
 	}
+	header.WriteString(")\n\n")
 
-	return processNode(rootNode, structName, "", withJsonTag)
+	return header.String()
 }
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func generate(t *testing.T, yamlContent string) string {
+	t.Helper()
+	withJsonTag := false
+	out, err := GenerateGoStruct(yamlContent, "Root", &withJsonTag)
+	if err != nil {
+		t.Fatalf("GenerateGoStruct: %v", err)
+	}
+	return out
+}
+
+func TestSharedAnchorReusesSingleStruct(t *testing.T) {
+	out := generate(t, `
+a: &x
+  name: foo
+b: *x
+`)
+
+	// Root itself plus the one struct shared by fields A and B.
+	if got := strings.Count(out, "type "); got != 2 {
+		t.Fatalf("expected the shared anchor to add exactly one struct type, got %d:\n%s", got, out)
+	}
+	if !strings.Contains(out, "A RootA") || !strings.Contains(out, "B RootA") {
+		t.Fatalf("expected fields A and B to share the same struct type:\n%s", out)
+	}
+}
+
+func TestCyclicMergeKeyReturnsError(t *testing.T) {
+	withJsonTag := false
+	_, err := GenerateGoStruct(`
+a: &x
+  k: 1
+  <<: *x
+`, "Root", &withJsonTag)
+	if err == nil {
+		t.Fatal("expected an error for a self-referencing merge key, got nil")
+	}
+}
+
+func TestHeterogeneousArrayUnifiesFields(t *testing.T) {
+	out := generate(t, `
+items:
+  - a: 1
+  - a: 1
+    b: 2
+`)
+
+	if !strings.Contains(out, "A int") {
+		t.Fatalf("expected field A present in every element to be a plain int:\n%s", out)
+	}
+	if !strings.Contains(out, "B *int `yaml:\"b,omitempty\"`") {
+		t.Fatalf("expected field B present in only some elements to be an optional pointer:\n%s", out)
+	}
+}
+
+func TestDuplicateShapeReusesStruct(t *testing.T) {
+	out := generate(t, `
+foo:
+  name: a
+bar:
+  name: b
+`)
+
+	// Root itself plus the one struct shared by foo and bar.
+	if got := strings.Count(out, "type "); got != 2 {
+		t.Fatalf("expected the two structurally identical mappings to share one struct type, got %d:\n%s", got, out)
+	}
+}
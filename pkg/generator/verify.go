@@ -0,0 +1,270 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// VerifyReport summarizes how faithfully generatedCode round-trips the YAML
+// it was generated from: fields the struct dropped, fields whose Go type
+// doesn't match what was in the YAML, and values that changed shape on the
+// way out (e.g. precision lost by a narrower numeric type).
+type VerifyReport struct {
+	LostFields     []string
+	TypeMismatches []string
+	PrecisionLoss  []string
+}
+
+// OK reports whether the round-trip was lossless.
+func (r *VerifyReport) OK() bool {
+	return len(r.LostFields) == 0 && len(r.TypeMismatches) == 0 && len(r.PrecisionLoss) == 0
+}
+
+// Verify compiles generatedCode in memory, unmarshals yamlContent into the
+// named struct, re-marshals it, and diffs the result against the original
+// YAML tree. It gives callers a testable contract for the type-inference
+// and unification passes: a clean report means the generated struct is
+// lossless for this input.
+func Verify(yamlContent string, generatedCode string, structName string) (*VerifyReport, error) {
+	structType, err := compileStruct(generatedCode, structName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile generated code: %w", err)
+	}
+
+	value := reflect.New(structType)
+	if err := yaml.Unmarshal([]byte(yamlContent), value.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal YAML into %s: %w", structName, err)
+	}
+
+	roundTripped, err := yaml.Marshal(value.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-marshal %s: %w", structName, err)
+	}
+
+	var original, after yaml.Node
+	if err := yaml.Unmarshal([]byte(yamlContent), &original); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(roundTripped, &after); err != nil {
+		return nil, err
+	}
+
+	origRoot, err := rootMappingOf(&original)
+	if err != nil {
+		return nil, err
+	}
+	afterRoot, err := rootMappingOf(&after)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &VerifyReport{}
+	diffNodes("", origRoot, afterRoot, report)
+	return report, nil
+}
+
+// compileStruct type-checks generatedCode and builds a runtime reflect.Type
+// for structName using go/types + reflect.StructOf, so Verify can
+// yaml.Unmarshal into a real value of the generated shape without writing
+// generatedCode to disk and invoking the go toolchain.
+func compileStruct(generatedCode string, structName string) (reflect.Type, error) {
+	src := "package generated\n\n" + generatedCode
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "generated.go", src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := types.Config{Importer: importer.Default()}
+	pkg, err := conf.Check("generated", fset, []*ast.File{file}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := pkg.Scope().Lookup(structName)
+	if obj == nil {
+		return nil, fmt.Errorf("struct %s not found in generated code", structName)
+	}
+
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a named type", structName)
+	}
+
+	return reflectStructType(named, map[string]reflect.Type{})
+}
+
+// reflectStructType builds a runtime struct type for named, recursing into
+// nested named struct fields. cache memoizes by type name and also detects
+// cycles: reflect.StructOf cannot construct a genuinely self-referential
+// struct, since a pointer field needs its pointee's reflect.Type already
+// built.
+func reflectStructType(named *types.Named, cache map[string]reflect.Type) (reflect.Type, error) {
+	name := named.Obj().Name()
+	if t, ok := cache[name]; ok {
+		if t == nil {
+			return nil, fmt.Errorf("cannot verify %s: reflect cannot construct a recursive struct type", name)
+		}
+		return t, nil
+	}
+	cache[name] = nil // mark as being built, to detect cycles
+
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a struct", name)
+	}
+
+	fields := make([]reflect.StructField, st.NumFields())
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		fieldType, err := reflectFieldType(v.Type(), cache)
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", name, v.Name(), err)
+		}
+		fields[i] = reflect.StructField{
+			Name: v.Name(),
+			Type: fieldType,
+			Tag:  reflect.StructTag(st.Tag(i)),
+		}
+	}
+
+	structType := reflect.StructOf(fields)
+	cache[name] = structType
+	return structType, nil
+}
+
+// reflectFieldType maps a go/types.Type produced by the generator's own
+// output (basics, slices, pointers, time.Time/time.Duration, and nested
+// named structs) to a reflect.Type.
+func reflectFieldType(t types.Type, cache map[string]reflect.Type) (reflect.Type, error) {
+	switch t := t.(type) {
+	case *types.Basic:
+		switch t.Kind() {
+		case types.Bool:
+			return reflect.TypeOf(false), nil
+		case types.Int:
+			return reflect.TypeOf(int(0)), nil
+		case types.Float64:
+			return reflect.TypeOf(float64(0)), nil
+		case types.String:
+			return reflect.TypeOf(""), nil
+		case types.Uint8:
+			return reflect.TypeOf(uint8(0)), nil
+		default:
+			return nil, fmt.Errorf("unsupported scalar type %s", t)
+		}
+	case *types.Slice:
+		elem, err := reflectFieldType(t.Elem(), cache)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(elem), nil
+	case *types.Pointer:
+		elem, err := reflectFieldType(t.Elem(), cache)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.PtrTo(elem), nil
+	case *types.Interface:
+		return reflect.TypeOf((*interface{})(nil)).Elem(), nil
+	case *types.Named:
+		if pkg := t.Obj().Pkg(); pkg != nil && pkg.Path() == "time" {
+			switch t.Obj().Name() {
+			case "Time":
+				return reflect.TypeOf(time.Time{}), nil
+			case "Duration":
+				return reflect.TypeOf(time.Duration(0)), nil
+			}
+		}
+		return reflectStructType(t, cache)
+	default:
+		return nil, fmt.Errorf("unsupported field type %s", t)
+	}
+}
+
+// diffNodes walks the original and round-tripped YAML trees in lockstep,
+// recording fields that disappeared, nodes whose kind/tag no longer match,
+// and scalar values that changed (e.g. precision loss).
+func diffNodes(path string, a, b *yaml.Node, report *VerifyReport) {
+	a = resolveAlias(a)
+	b = resolveAlias(b)
+
+	if a.Kind != b.Kind {
+		report.TypeMismatches = append(report.TypeMismatches,
+			fmt.Sprintf("%s: kind changed (%s -> %s)", displayPath(path), kindName(a.Kind), kindName(b.Kind)))
+		return
+	}
+
+	switch a.Kind {
+	case yaml.MappingNode:
+		afterFields := map[string]*yaml.Node{}
+		for i := 0; i+1 < len(b.Content); i += 2 {
+			afterFields[b.Content[i].Value] = b.Content[i+1]
+		}
+		for i := 0; i+1 < len(a.Content); i += 2 {
+			key := a.Content[i].Value
+			childPath := joinPath(path, key)
+			afterVal, ok := afterFields[key]
+			if !ok {
+				report.LostFields = append(report.LostFields, displayPath(childPath))
+				continue
+			}
+			diffNodes(childPath, a.Content[i+1], afterVal, report)
+		}
+	case yaml.SequenceNode:
+		for i, elem := range a.Content {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			if i >= len(b.Content) {
+				report.LostFields = append(report.LostFields, displayPath(childPath))
+				continue
+			}
+			diffNodes(childPath, elem, b.Content[i], report)
+		}
+	case yaml.ScalarNode:
+		if a.Tag != b.Tag {
+			report.TypeMismatches = append(report.TypeMismatches,
+				fmt.Sprintf("%s: tag changed (%s -> %s)", displayPath(path), a.Tag, b.Tag))
+			return
+		}
+		if a.Value != b.Value {
+			report.PrecisionLoss = append(report.PrecisionLoss,
+				fmt.Sprintf("%s: value changed (%q -> %q)", displayPath(path), a.Value, b.Value))
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+func kindName(k yaml.Kind) string {
+	switch k {
+	case yaml.ScalarNode:
+		return "scalar"
+	case yaml.MappingNode:
+		return "mapping"
+	case yaml.SequenceNode:
+		return "sequence"
+	default:
+		return "unknown"
+	}
+}